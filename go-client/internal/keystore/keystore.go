@@ -0,0 +1,98 @@
+// Package keystore lets the vault CLI load a signing key from an encrypted
+// Web3 secret-storage v3 JSON file instead of a plaintext PRIVATE_KEY env
+// var. It's a thin wrapper over go-ethereum's accounts/keystore, which
+// already implements the v3 format (scrypt KDF, AES-128-CTR, ciphertext
+// MAC) this tool depends on anyway.
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"os"
+	"strings"
+
+	gethks "github.com/ethereum/go-ethereum/accounts/keystore"
+	"golang.org/x/term"
+)
+
+// Store manages keystore v3 files in a directory.
+type Store struct {
+	ks *gethks.KeyStore
+}
+
+// Open opens (creating if necessary) the keystore directory at dir.
+func Open(dir string) *Store {
+	return &Store{ks: gethks.NewKeyStore(dir, gethks.StandardScryptN, gethks.StandardScryptP)}
+}
+
+// New generates a fresh private key and stores it encrypted with
+// passphrase, returning the address of the new account.
+func (s *Store) New(passphrase string) (string, error) {
+	account, err := s.ks.NewAccount(passphrase)
+	if err != nil {
+		return "", fmt.Errorf("generate new key: %w", err)
+	}
+	return account.Address.Hex(), nil
+}
+
+// Import decrypts keyJSON with passphrase and re-encrypts it into this
+// store under newPassphrase, returning the address of the imported
+// account.
+func (s *Store) Import(keyJSON []byte, passphrase, newPassphrase string) (string, error) {
+	account, err := s.ks.Import(keyJSON, passphrase, newPassphrase)
+	if err != nil {
+		return "", fmt.Errorf("import key: %w", err)
+	}
+	return account.Address.Hex(), nil
+}
+
+// List returns the hex addresses of every account in the store.
+func (s *Store) List() []string {
+	accounts := s.ks.Accounts()
+	addrs := make([]string, len(accounts))
+	for i, a := range accounts {
+		addrs[i] = a.Address.Hex()
+	}
+	return addrs
+}
+
+// LoadPrivateKey decrypts the keystore v3 JSON file at keystorePath using
+// the passphrase read from passwordFile (or, if passwordFile is empty, an
+// interactive terminal prompt) and returns the resulting private key.
+func LoadPrivateKey(keystorePath, passwordFile string) (*ecdsa.PrivateKey, error) {
+	keyJSON, err := os.ReadFile(keystorePath)
+	if err != nil {
+		return nil, fmt.Errorf("read keystore file: %w", err)
+	}
+
+	passphrase, err := ReadPassphrase(passwordFile)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := gethks.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt keystore file: %w", err)
+	}
+	return key.PrivateKey, nil
+}
+
+// ReadPassphrase reads a passphrase from passwordFile, or prompts on the
+// terminal with echo disabled when passwordFile is empty.
+func ReadPassphrase(passwordFile string) (string, error) {
+	if passwordFile != "" {
+		content, err := os.ReadFile(passwordFile)
+		if err != nil {
+			return "", fmt.Errorf("read password file: %w", err)
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+
+	fmt.Fprint(os.Stderr, "Keystore passphrase: ")
+	raw, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("read passphrase: %w", err)
+	}
+	return string(raw), nil
+}
@@ -0,0 +1,217 @@
+// Package resolve lets callers pass human-readable names instead of raw
+// addresses and bytes32 IDs: ENS names for the secret owner, and symbolic
+// names for a provider, looked up against an on-chain ProviderRegistry
+// (falling back to keccak256(name) when the registry doesn't know it).
+package resolve
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+func callMsg(to common.Address, data []byte) ethereum.CallMsg {
+	return ethereum.CallMsg{To: &to, Data: data}
+}
+
+// Resolver turns human-readable owner and provider identifiers into the
+// address and bytes32 ID the contract expects.
+type Resolver interface {
+	ResolveOwner(ctx context.Context, input string) (common.Address, error)
+	ResolveProvider(ctx context.Context, input string) (common.Hash, error)
+}
+
+const ensRegistryABIJSON = `[
+	{"inputs":[{"name":"node","type":"bytes32"}],"name":"resolver","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"}
+]`
+
+const ensResolverABIJSON = `[
+	{"inputs":[{"name":"node","type":"bytes32"}],"name":"addr","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"}
+]`
+
+const providerRegistryABIJSON = `[
+	{"inputs":[{"name":"name","type":"string"}],"name":"idFor","outputs":[{"name":"","type":"bytes32"}],"stateMutability":"view","type":"function"}
+]`
+
+// ChainResolver resolves names against an ENS deployment and an on-chain
+// ProviderRegistry contract.
+type ChainResolver struct {
+	client           *ethclient.Client
+	ensRegistry      common.Address
+	providerRegistry common.Address
+
+	ensRegistryABI      abi.ABI
+	ensResolverABI      abi.ABI
+	providerRegistryABI abi.ABI
+}
+
+// NewChainResolver builds a resolver backed by client, using ensRegistry
+// for name resolution and providerRegistry for symbolic provider IDs.
+// Either address may be the zero address to disable that half of the
+// resolver; ResolveProvider still falls back to keccak256(name) in that
+// case.
+func NewChainResolver(client *ethclient.Client, ensRegistry, providerRegistry common.Address) (*ChainResolver, error) {
+	regABI, err := abi.JSON(strings.NewReader(ensRegistryABIJSON))
+	if err != nil {
+		return nil, fmt.Errorf("parse ENS registry ABI: %w", err)
+	}
+	resABI, err := abi.JSON(strings.NewReader(ensResolverABIJSON))
+	if err != nil {
+		return nil, fmt.Errorf("parse ENS resolver ABI: %w", err)
+	}
+	provABI, err := abi.JSON(strings.NewReader(providerRegistryABIJSON))
+	if err != nil {
+		return nil, fmt.Errorf("parse provider registry ABI: %w", err)
+	}
+
+	return &ChainResolver{
+		client:              client,
+		ensRegistry:         ensRegistry,
+		providerRegistry:    providerRegistry,
+		ensRegistryABI:      regABI,
+		ensResolverABI:      resABI,
+		providerRegistryABI: provABI,
+	}, nil
+}
+
+// ResolveOwner returns input unchanged (as an address) when it already
+// parses as a common.Address, and otherwise resolves it as an ENS name via
+// namehash -> registry.resolver() -> resolver.addr().
+func (r *ChainResolver) ResolveOwner(ctx context.Context, input string) (common.Address, error) {
+	if common.IsHexAddress(input) {
+		return common.HexToAddress(input), nil
+	}
+
+	node := namehash(input)
+
+	resolverAddr, err := r.callAddress(ctx, r.ensRegistry, r.ensRegistryABI, "resolver", node)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("resolve ENS resolver for %q: %w", input, err)
+	}
+	if resolverAddr == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("%q has no ENS resolver set", input)
+	}
+
+	addr, err := r.callAddress(ctx, resolverAddr, r.ensResolverABI, "addr", node)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("resolve ENS address for %q: %w", input, err)
+	}
+	return addr, nil
+}
+
+// ResolveProvider returns input unchanged (as a bytes32 ID) when it already
+// parses as a 0x-prefixed 32-byte hex string, and otherwise looks it up in
+// the ProviderRegistry, falling back to keccak256(input) only when the
+// registry is unset or genuinely doesn't recognize the name. Registry/RPC
+// errors are propagated rather than treated as "unregistered", so a flaky
+// node can't make the client silently fetch the wrong providerId. Any other
+// 0x-prefixed input (bad hex or the wrong length) is rejected with an error
+// and is neither looked up nor hashed.
+func (r *ChainResolver) ResolveProvider(ctx context.Context, input string) (common.Hash, error) {
+	if id, looksLikeID, err := parseProviderID(input); looksLikeID {
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("invalid provider ID %q: %w", input, err)
+		}
+		return id, nil
+	}
+
+	if r.providerRegistry != (common.Address{}) {
+		id, registered, err := r.lookupProviderID(ctx, input)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("look up provider %q in registry: %w", input, err)
+		}
+		if registered {
+			return id, nil
+		}
+	}
+
+	return crypto.Keccak256Hash([]byte(input)), nil
+}
+
+// lookupProviderID queries the ProviderRegistry for name, returning
+// registered=false only when the registry call succeeds and reports the
+// zero ID (i.e. name is genuinely unregistered).
+func (r *ChainResolver) lookupProviderID(ctx context.Context, name string) (id common.Hash, registered bool, err error) {
+	data, err := r.providerRegistryABI.Pack("idFor", name)
+	if err != nil {
+		return common.Hash{}, false, err
+	}
+	result, err := r.client.CallContract(ctx, callMsg(r.providerRegistry, data), nil)
+	if err != nil {
+		return common.Hash{}, false, err
+	}
+	out, err := r.providerRegistryABI.Unpack("idFor", result)
+	if err != nil {
+		return common.Hash{}, false, err
+	}
+	rawID, ok := out[0].([32]byte)
+	if !ok {
+		return common.Hash{}, false, fmt.Errorf("unexpected return type for idFor")
+	}
+	if rawID == ([32]byte{}) {
+		return common.Hash{}, false, nil
+	}
+	return common.Hash(rawID), true, nil
+}
+
+func (r *ChainResolver) callAddress(ctx context.Context, to common.Address, contractABI abi.ABI, method string, args ...interface{}) (common.Address, error) {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return common.Address{}, err
+	}
+	result, err := r.client.CallContract(ctx, callMsg(to, data), nil)
+	if err != nil {
+		return common.Address{}, err
+	}
+	out, err := contractABI.Unpack(method, result)
+	if err != nil {
+		return common.Address{}, err
+	}
+	addr, ok := out[0].(common.Address)
+	if !ok {
+		return common.Address{}, fmt.Errorf("unexpected return type for %s", method)
+	}
+	return addr, nil
+}
+
+// namehash implements the standard ENS name-hashing algorithm (EIP-137).
+func namehash(name string) common.Hash {
+	var node common.Hash
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := crypto.Keccak256Hash([]byte(labels[i]))
+		node = crypto.Keccak256Hash(node.Bytes(), labelHash.Bytes())
+	}
+	return node
+}
+
+// parseProviderID decodes input as a bytes32 providerId if it looks like
+// one (0x-prefixed). looksLikeID is false for anything else, meaning input
+// should be treated as a name instead. When looksLikeID is true but err is
+// non-nil, input had a "0x" prefix but wasn't valid 32-byte hex, so callers
+// must not silently fall back to treating it as a literal ID (e.g. via
+// common.HexToHash, which zero-fills invalid nibbles instead of erroring).
+func parseProviderID(input string) (id common.Hash, looksLikeID bool, err error) {
+	if !strings.HasPrefix(input, "0x") {
+		return common.Hash{}, false, nil
+	}
+
+	decoded, err := hexutil.Decode(input)
+	if err != nil {
+		return common.Hash{}, true, err
+	}
+	if len(decoded) != common.HashLength {
+		return common.Hash{}, true, fmt.Errorf("expected %d bytes, got %d", common.HashLength, len(decoded))
+	}
+	return common.BytesToHash(decoded), true, nil
+}
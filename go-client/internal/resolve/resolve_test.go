@@ -0,0 +1,35 @@
+package resolve
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseProviderID(t *testing.T) {
+	valid := "0x" + strings.Repeat("ab", 32)
+	malformed := "0xzz" + strings.Repeat("ab", 31)
+
+	tests := []struct {
+		name        string
+		input       string
+		wantLooksID bool
+		wantErr     bool
+	}{
+		{name: "valid bytes32", input: valid, wantLooksID: true, wantErr: false},
+		{name: "plain name", input: "OPENAI_API_KEY", wantLooksID: false, wantErr: false},
+		{name: "malformed hex", input: malformed, wantLooksID: true, wantErr: true},
+		{name: "wrong length", input: "0x1234", wantLooksID: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, looksLikeID, err := parseProviderID(tt.input)
+			if looksLikeID != tt.wantLooksID {
+				t.Fatalf("looksLikeID = %v, want %v", looksLikeID, tt.wantLooksID)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
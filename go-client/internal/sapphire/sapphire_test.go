@@ -0,0 +1,34 @@
+package sapphire
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestDigest pins the EIP-712 digest computation to a fixed vector so a
+// future change to the domain, Call, or Leash encoding doesn't silently
+// drift from the wire format sapphire-paratime nodes expect.
+func TestDigest(t *testing.T) {
+	pack := &SignedCallDataPack{
+		ChainID:  big.NewInt(23295),
+		From:     common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		To:       common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		GasLimit: 100000,
+		GasPrice: big.NewInt(0),
+		Value:    big.NewInt(0),
+		Data:     []byte{0xde, 0xad, 0xbe, 0xef},
+		Leash: Leash{
+			Nonce:       7,
+			BlockNumber: 42,
+			BlockHash:   common.HexToHash("0x33"),
+			BlockRange:  blockRange,
+		},
+	}
+
+	want := "0x4ac4800b0901b27545c56dff91b045d684e11032efe56a32ca2f2cf7b48ff937"
+	if got := pack.Digest().Hex(); got != want {
+		t.Fatalf("Digest() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,281 @@
+// Package watch implements `vault watch`: a long-running subscriber that
+// turns on-chain SecretUpdated events into push notifications, so
+// downstream services don't have to poll getSecretInfo for rotations.
+package watch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// secretUpdatedSig is the topic0 of SecretUpdated(address indexed owner,
+// bytes32 indexed providerId, uint64 version).
+var secretUpdatedSig = crypto.Keccak256Hash([]byte("SecretUpdated(address,bytes32,uint64)"))
+
+const eventABIJSON = `[
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "owner", "type": "address"},
+			{"indexed": true, "name": "providerId", "type": "bytes32"},
+			{"indexed": false, "name": "version", "type": "uint64"}
+		],
+		"name": "SecretUpdated",
+		"type": "event"
+	}
+]`
+
+// Config controls a watch run.
+type Config struct {
+	WSURL          string
+	ContractAddr   common.Address
+	ProviderIDs    []common.Hash // empty means watch every providerId
+	CheckpointPath string
+	WebhookURL     string
+	ExecHook       string
+}
+
+// SecretUpdated is the decoded form of a SecretUpdated log.
+type SecretUpdated struct {
+	Owner       common.Address `json:"owner"`
+	ProviderID  common.Hash    `json:"providerId"`
+	Version     uint64         `json:"version"`
+	BlockNumber uint64         `json:"blockNumber"`
+	TxHash      common.Hash    `json:"txHash"`
+}
+
+// ProviderID returns the keccak256 bytes32 ID for a human-readable provider
+// name, the same way the contract derives it.
+func ProviderID(name string) common.Hash {
+	return crypto.Keccak256Hash([]byte(name))
+}
+
+// Run subscribes to SecretUpdated events on cfg.ContractAddr and invokes
+// cfg's webhook/exec hook for every matching event, reconnecting with
+// exponential backoff until ctx is canceled.
+func Run(ctx context.Context, cfg Config) error {
+	eventABI, err := abi.JSON(strings.NewReader(eventABIJSON))
+	if err != nil {
+		return fmt.Errorf("parse event ABI: %w", err)
+	}
+
+	fromBlock := loadCheckpoint(cfg.CheckpointPath)
+
+	backoff := time.Second
+	const maxBackoff = 30 * time.Second
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		fromBlock, err = watchOnce(ctx, cfg, eventABI, fromBlock)
+		if err != nil {
+			log.Printf("watch: connection lost: %v (retrying in %s)", err, backoff)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			continue
+		}
+
+		backoff = time.Second
+	}
+}
+
+// watchOnce opens one websocket subscription, backfills everything emitted
+// since fromBlock, then processes the live feed until it drops or ctx is
+// canceled, returning the block to resume from next time.
+func watchOnce(ctx context.Context, cfg Config, eventABI abi.ABI, fromBlock uint64) (uint64, error) {
+	client, err := ethclient.DialContext(ctx, cfg.WSURL)
+	if err != nil {
+		return fromBlock, fmt.Errorf("dial websocket: %w", err)
+	}
+	defer client.Close()
+
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{cfg.ContractAddr},
+		Topics:    [][]common.Hash{{secretUpdatedSig}, nil, providerTopics(cfg.ProviderIDs)},
+	}
+
+	// Subscribe before backfilling: SubscribeFilterLogs only forwards logs
+	// mined after the subscription is installed (FromBlock isn't used to
+	// backfill), so anything mined between here and the BlockNumber call
+	// below is still caught live.
+	logsCh := make(chan types.Log)
+	sub, err := client.SubscribeFilterLogs(ctx, query, logsCh)
+	if err != nil {
+		return fromBlock, fmt.Errorf("subscribe filter logs: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	latest, err := client.BlockNumber(ctx)
+	if err != nil {
+		return fromBlock, fmt.Errorf("fetch latest block: %w", err)
+	}
+	if latest >= fromBlock {
+		backfillQuery := query
+		backfillQuery.FromBlock = new(big.Int).SetUint64(fromBlock)
+		backfillQuery.ToBlock = new(big.Int).SetUint64(latest)
+
+		history, err := client.FilterLogs(ctx, backfillQuery)
+		if err != nil {
+			return fromBlock, fmt.Errorf("backfill filter logs: %w", err)
+		}
+		for _, vLog := range history {
+			fromBlock = processLog(cfg, eventABI, vLog, fromBlock)
+		}
+	}
+
+	log.Printf("watch: subscribed from block %d", fromBlock)
+
+	for {
+		select {
+		case err := <-sub.Err():
+			return fromBlock, err
+		case vLog := <-logsCh:
+			fromBlock = processLog(cfg, eventABI, vLog, fromBlock)
+		case <-ctx.Done():
+			return fromBlock, ctx.Err()
+		}
+	}
+}
+
+// processLog decodes and dispatches a single log, advances fromBlock past
+// it, and persists the checkpoint. It's shared by the historical backfill
+// and the live subscription loop.
+func processLog(cfg Config, eventABI abi.ABI, vLog types.Log, fromBlock uint64) uint64 {
+	event, err := decodeSecretUpdated(eventABI, vLog)
+	if err != nil {
+		log.Printf("watch: failed to decode log: %v", err)
+		return fromBlock
+	}
+
+	if err := dispatch(cfg, event); err != nil {
+		log.Printf("watch: hook failed for %s/%s: %v", event.Owner.Hex(), event.ProviderID.Hex(), err)
+	}
+
+	// FromBlock is inclusive, so resume one block past this event or a
+	// reconnect/restart would redeliver it and re-fire the hook. Logs can
+	// arrive out of order across the backfill/live boundary, so only move
+	// the checkpoint forward.
+	if next := vLog.BlockNumber + 1; next > fromBlock {
+		fromBlock = next
+	}
+	saveCheckpoint(cfg.CheckpointPath, fromBlock)
+	return fromBlock
+}
+
+// providerTopics returns nil (meaning "match any") when no provider filter
+// was requested, since an empty non-nil slice would match nothing.
+func providerTopics(ids []common.Hash) []common.Hash {
+	if len(ids) == 0 {
+		return nil
+	}
+	return ids
+}
+
+func decodeSecretUpdated(eventABI abi.ABI, vLog types.Log) (SecretUpdated, error) {
+	var decoded struct {
+		Version uint64
+	}
+	if err := eventABI.UnpackIntoInterface(&decoded, "SecretUpdated", vLog.Data); err != nil {
+		return SecretUpdated{}, err
+	}
+
+	return SecretUpdated{
+		Owner:       common.BytesToAddress(vLog.Topics[1].Bytes()),
+		ProviderID:  vLog.Topics[2],
+		Version:     decoded.Version,
+		BlockNumber: vLog.BlockNumber,
+		TxHash:      vLog.TxHash,
+	}, nil
+}
+
+func dispatch(cfg Config, event SecretUpdated) error {
+	if cfg.WebhookURL != "" {
+		if err := postWebhook(cfg.WebhookURL, event); err != nil {
+			return fmt.Errorf("webhook: %w", err)
+		}
+	}
+	if cfg.ExecHook != "" {
+		if err := runExecHook(cfg.ExecHook, event); err != nil {
+			return fmt.Errorf("exec hook: %w", err)
+		}
+	}
+	return nil
+}
+
+func postWebhook(url string, event SecretUpdated) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func runExecHook(hookCmd string, event SecretUpdated) error {
+	cmd := exec.Command("sh", "-c", hookCmd)
+	cmd.Env = append(os.Environ(),
+		"VAULT_EVENT_OWNER="+event.Owner.Hex(),
+		"VAULT_EVENT_PROVIDER_ID="+event.ProviderID.Hex(),
+		fmt.Sprintf("VAULT_EVENT_VERSION=%d", event.Version),
+		fmt.Sprintf("VAULT_EVENT_BLOCK=%d", event.BlockNumber),
+	)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func loadCheckpoint(path string) uint64 {
+	if path == "" {
+		return 0
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	var block uint64
+	if _, err := fmt.Sscanf(string(data), "%d", &block); err != nil {
+		return 0
+	}
+	return block
+}
+
+func saveCheckpoint(path string, block uint64) {
+	if path == "" {
+		return
+	}
+	if err := os.WriteFile(path, []byte(fmt.Sprintf("%d", block)), 0644); err != nil {
+		log.Printf("watch: failed to save checkpoint: %v", err)
+	}
+}
@@ -3,23 +3,38 @@ package main
 import (
 	"context"
 	"crypto/ecdsa"
+	"flag"
 	"fmt"
 	"log"
-	"math/big"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/ekailabs/api-vault/go-client/internal/eip712"
+	"github.com/ekailabs/api-vault/go-client/internal/keystore"
+	"github.com/ekailabs/api-vault/go-client/internal/resolve"
+	"github.com/ekailabs/api-vault/go-client/internal/sapphire"
+	"github.com/ekailabs/api-vault/go-client/internal/watch"
 )
 
 const (
 	contractAddr = "0x440222b531537ac1A90dbDF906D36Be0536e4Ec8"
 	ownerAddr    = "0x4Ec6E3b99E2E4422d6e64313F5AA2A8470DCDa2b"
 	rpcURL       = "https://testnet.sapphire.oasis.io"
+	wsRPCURL     = "wss://testnet.sapphire.oasis.io/ws"
+
+	// ensRegistryAddr and providerRegistryAddr are left unset since neither
+	// is deployed on Sapphire testnet; set them to enable ENS owner names
+	// and symbolic provider names respectively. --owner/--provider still
+	// accept raw addresses and bytes32 IDs either way.
+	ensRegistryAddr      = ""
+	providerRegistryAddr = ""
 )
 
 // Minimal ABI
@@ -37,19 +52,49 @@ const abiJSON = `[
 		"outputs": [{"name": "version", "type": "uint64"},{"name": "exists", "type": "bool"},{"name": "isAllowed", "type": "bool"}],
 		"stateMutability": "view",
 		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "owner", "type": "address"},
+			{"name": "providerId", "type": "bytes32"},
+			{"name": "signer", "type": "address"},
+			{"name": "expiry", "type": "uint64"},
+			{"name": "nonce", "type": "uint64"},
+			{"name": "signature", "type": "bytes"}
+		],
+		"name": "getSecretWithGrant",
+		"outputs": [{"name": "", "type": "bytes"}],
+		"stateMutability": "view",
+		"type": "function"
 	}
 ]`
 
+// eip712Domain scopes SecretGrant signatures to this contract.
+var eip712Domain = eip712.Domain{
+	Name:    "APIVault",
+	Version: "1",
+}
+
 func main() {
-	privateKeyHex := os.Getenv("PRIVATE_KEY")
-	if privateKeyHex == "" {
-		log.Fatal("PRIVATE_KEY env var required")
+	if len(os.Args) > 1 && os.Args[1] == "keystore" {
+		runKeystoreCommand(os.Args[2:])
+		return
 	}
-	privateKeyHex = strings.TrimPrefix(privateKeyHex, "0x")
+	if len(os.Args) > 1 && os.Args[1] == "watch" {
+		runWatchCommand(os.Args[2:])
+		return
+	}
+
+	fs := flag.NewFlagSet("vault", flag.ExitOnError)
+	keystorePath := fs.String("keystore", "", "path to an encrypted keystore v3 JSON file")
+	passwordFile := fs.String("password-file", "", "path to a file containing the keystore passphrase (prompted interactively if omitted)")
+	ownerFlag := fs.String("owner", ownerAddr, "secret owner: a 0x address or an ENS name (e.g. alice.eth)")
+	providerFlag := fs.String("provider", "OPENAI_API_KEY", "provider: a symbolic name, or a 0x-prefixed bytes32 ID")
+	fs.Parse(os.Args[1:])
 
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	privateKey, err := loadSigningKey(*keystorePath, *passwordFile)
 	if err != nil {
-		log.Fatalf("Invalid private key: %v", err)
+		log.Fatalf("Failed to load signing key: %v", err)
 	}
 
 	publicKey := privateKey.Public().(*ecdsa.PublicKey)
@@ -63,7 +108,10 @@ func main() {
 	}
 	defer client.Close()
 
-	chainID, _ := client.ChainID(context.Background())
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to fetch chain ID: %v", err)
+	}
 	fmt.Printf("Connected to chain ID: %v\n\n", chainID)
 
 	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
@@ -72,8 +120,26 @@ func main() {
 	}
 
 	contract := common.HexToAddress(contractAddr)
-	owner := common.HexToAddress(ownerAddr)
-	providerId := crypto.Keccak256Hash([]byte("OPENAI_API_KEY"))
+
+	var ensRegistry, providerRegistry common.Address
+	if ensRegistryAddr != "" {
+		ensRegistry = common.HexToAddress(ensRegistryAddr)
+	}
+	if providerRegistryAddr != "" {
+		providerRegistry = common.HexToAddress(providerRegistryAddr)
+	}
+	resolver, err := resolve.NewChainResolver(client, ensRegistry, providerRegistry)
+	if err != nil {
+		log.Fatalf("Failed to set up resolver: %v", err)
+	}
+	owner, err := resolver.ResolveOwner(context.Background(), *ownerFlag)
+	if err != nil {
+		log.Fatalf("Failed to resolve owner %q: %v", *ownerFlag, err)
+	}
+	providerId, err := resolver.ResolveProvider(context.Background(), *providerFlag)
+	if err != nil {
+		log.Fatalf("Failed to resolve provider %q: %v", *providerFlag, err)
+	}
 
 	// Test: Unsigned call (should show isAllowed = false)
 	fmt.Println("--- Unsigned eth_call (for comparison) ---")
@@ -90,12 +156,154 @@ func main() {
 		fmt.Printf("Version: %v, Exists: %v, IsAllowed: %v\n", res[0], res[1], res[2])
 	}
 
-	// For signed queries, we need sapphire-paratime Go client
-	// The issue is the dependency - let me try building from Oasis example
-	fmt.Println("\n--- Note ---")
-	fmt.Println("To test signed queries, we need the sapphire-paratime Go client.")
-	fmt.Println("The dependency has version conflicts. Let me try the Python client instead.")
+	// Signed eth_call (reveals the decrypted secret once isAllowed gates on
+	// msg.sender, which Sapphire can only authenticate via a signed query).
+	fmt.Println("\n--- Signed eth_call ---")
+	sapphireClient := sapphire.NewClient(client)
 
-	_ = privateKey
-	_ = big.NewInt(0)
+	getSecretData, _ := parsedABI.Pack("getSecret", owner, providerId)
+	secretResult, err := sapphireClient.CallContractSigned(context.Background(), ethereum.CallMsg{
+		To:   &contract,
+		Data: getSecretData,
+	}, privateKey)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	} else {
+		res, _ := parsedABI.Unpack("getSecret", secretResult)
+		fmt.Printf("Secret: %s\n", res[0])
+	}
+
+	// getSecret authorized by a pre-signed EIP-712 grant instead of a
+	// Sapphire signed query, e.g. when owner delegates fetch rights to a
+	// downstream service without sharing their key.
+	fmt.Println("\n--- getSecretWithGrant (EIP-712 delegation) ---")
+	domain := eip712Domain
+	domain.ChainID = chainID
+	domain.VerifyingContract = contract
+
+	grant := eip712.SecretGrant{
+		Owner:      owner,
+		Signer:     signerAddr,
+		ProviderID: providerId,
+		Expiry:     uint64(time.Now().Add(1 * time.Hour).Unix()),
+		Nonce:      0,
+	}
+	grantSig, err := eip712.SignSecretGrant(privateKey, domain, grant)
+	if err != nil {
+		log.Fatalf("Failed to sign secret grant: %v", err)
+	}
+
+	grantCallData, _ := parsedABI.Pack("getSecretWithGrant", owner, providerId, grant.Signer, grant.Expiry, grant.Nonce, grantSig)
+	grantResult, err := client.CallContract(context.Background(), ethereum.CallMsg{
+		To:   &contract,
+		Data: grantCallData,
+	}, nil)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	} else {
+		res, _ := parsedABI.Unpack("getSecretWithGrant", grantResult)
+		fmt.Printf("Secret: %s\n", res[0])
+	}
+}
+
+// loadSigningKey resolves the signer's private key, preferring an encrypted
+// keystore over the legacy plaintext PRIVATE_KEY env var.
+func loadSigningKey(keystorePath, passwordFile string) (*ecdsa.PrivateKey, error) {
+	if keystorePath != "" {
+		return keystore.LoadPrivateKey(keystorePath, passwordFile)
+	}
+
+	privateKeyHex := os.Getenv("PRIVATE_KEY")
+	if privateKeyHex == "" {
+		return nil, fmt.Errorf("no signing key: pass --keystore, or set PRIVATE_KEY")
+	}
+	log.Println("Warning: PRIVATE_KEY env var is deprecated, use --keystore instead")
+
+	privateKeyHex = strings.TrimPrefix(privateKeyHex, "0x")
+	return crypto.HexToECDSA(privateKeyHex)
+}
+
+// runKeystoreCommand implements `vault keystore <new|import|list>`.
+func runKeystoreCommand(args []string) {
+	fs := flag.NewFlagSet("vault keystore", flag.ExitOnError)
+	dir := fs.String("dir", "./keystore", "keystore directory")
+	passwordFile := fs.String("password-file", "", "path to a file containing the passphrase (prompted interactively if omitted)")
+
+	if len(args) == 0 {
+		log.Fatal("usage: vault keystore <new|import|list> [flags]")
+	}
+	subcommand, rest := args[0], args[1:]
+	fs.Parse(rest)
+
+	store := keystore.Open(*dir)
+
+	switch subcommand {
+	case "new":
+		passphrase, err := keystore.ReadPassphrase(*passwordFile)
+		if err != nil {
+			log.Fatalf("Failed to read passphrase: %v", err)
+		}
+		addr, err := store.New(passphrase)
+		if err != nil {
+			log.Fatalf("Failed to create key: %v", err)
+		}
+		fmt.Printf("Created new keystore account: %s\n", addr)
+
+	case "import":
+		if fs.NArg() != 1 {
+			log.Fatal("usage: vault keystore import <path-to-key-json> [--password-file <path>]")
+		}
+		keyJSON, err := os.ReadFile(fs.Arg(0))
+		if err != nil {
+			log.Fatalf("Failed to read key file: %v", err)
+		}
+		passphrase, err := keystore.ReadPassphrase(*passwordFile)
+		if err != nil {
+			log.Fatalf("Failed to read passphrase: %v", err)
+		}
+		addr, err := store.Import(keyJSON, passphrase, passphrase)
+		if err != nil {
+			log.Fatalf("Failed to import key: %v", err)
+		}
+		fmt.Printf("Imported keystore account: %s\n", addr)
+
+	case "list":
+		for _, addr := range store.List() {
+			fmt.Println(addr)
+		}
+
+	default:
+		log.Fatalf("unknown keystore subcommand %q", subcommand)
+	}
+}
+
+// runWatchCommand implements `vault watch`, subscribing to SecretUpdated
+// events and pushing them to a webhook or exec hook as they arrive.
+func runWatchCommand(args []string) {
+	fs := flag.NewFlagSet("vault watch", flag.ExitOnError)
+	providers := fs.String("providers", "", "comma-separated provider names to watch (default: all)")
+	checkpointPath := fs.String("checkpoint", "./vault-watch.checkpoint", "file to persist the last-processed block to")
+	webhookURL := fs.String("webhook", "", "URL to POST SecretUpdated events to")
+	execHook := fs.String("exec", "", "shell command to run for each SecretUpdated event")
+	fs.Parse(args)
+
+	var providerIDs []common.Hash
+	if *providers != "" {
+		for _, name := range strings.Split(*providers, ",") {
+			providerIDs = append(providerIDs, watch.ProviderID(strings.TrimSpace(name)))
+		}
+	}
+
+	cfg := watch.Config{
+		WSURL:          wsRPCURL,
+		ContractAddr:   common.HexToAddress(contractAddr),
+		ProviderIDs:    providerIDs,
+		CheckpointPath: *checkpointPath,
+		WebhookURL:     *webhookURL,
+		ExecHook:       *execHook,
+	}
+
+	if err := watch.Run(context.Background(), cfg); err != nil {
+		log.Fatalf("watch stopped: %v", err)
+	}
 }